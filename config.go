@@ -1,9 +1,12 @@
 package servicerunner
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"github.com/kelseyhightower/envconfig"
 	"net/http"
+	"os"
 )
 
 func NewConfig(name string, port int) Config {
@@ -16,6 +19,11 @@ func NewConfig(name string, port int) Config {
 //	SERVER_NAME: default "server"
 //	SERVER_HOST: default "0.0.0.0"
 //	SERVER_PORT: default "8000"
+//	SERVER_INTROSPECTION_PORT: default "0" (disabled)
+//	SERVER_TLS_CERT_FILE: default "" (TLS disabled)
+//	SERVER_TLS_KEY_FILE: default ""
+//	SERVER_TLS_CLIENT_CA: default "" (mTLS disabled)
+//	SERVER_H2C: default "false"
 func NewEnvConfig() Config {
 	var def DefaultConfig
 	envconfig.MustProcess("SERVER", &def)
@@ -35,13 +43,28 @@ type Config interface {
 	Name() string
 	Host() string
 	Port() int
+	// IntrospectionPort returns the port the introspection server
+	// (/healthz, /readyz, /metrics, pprof) listens on, or 0 if disabled.
+	IntrospectionPort() int
 	addr() string
+	introspectionAddr() string
+	// tlsConfig builds the *tls.Config to serve with, or returns nil if no
+	// cert/key pair is configured.
+	tlsConfig() (*tls.Config, error)
+	// h2c reports whether the server should speak HTTP/2 over cleartext.
+	// It has no effect when tlsConfig returns non-nil.
+	h2c() bool
 }
 
 type DefaultConfig struct {
-	ServerName string `default:"server" envconfig:"name"`
-	HostName   string `default:"" envconfig:"host"`
-	ServerPort int    `default:"8000" envconfig:"port"`
+	ServerName              string `default:"server" envconfig:"name"`
+	HostName                string `default:"" envconfig:"host"`
+	ServerPort              int    `default:"8000" envconfig:"port"`
+	IntrospectionServerPort int    `default:"0" envconfig:"introspection_port"`
+	TLSCertFile             string `default:"" envconfig:"tls_cert_file"`
+	TLSKeyFile              string `default:"" envconfig:"tls_key_file"`
+	TLSClientCAFile         string `default:"" envconfig:"tls_client_ca"`
+	H2CEnabled              bool   `default:"false" envconfig:"h2c"`
 }
 
 func (c DefaultConfig) Server(mu http.Handler) *http.Server {
@@ -63,3 +86,52 @@ func (c DefaultConfig) Port() int {
 func (c DefaultConfig) addr() string {
 	return fmt.Sprintf("%s:%d", c.HostName, c.ServerPort)
 }
+
+func (c DefaultConfig) IntrospectionPort() int {
+	return c.IntrospectionServerPort
+}
+
+// introspectionAddr returns the introspection server's listen address, or ""
+// if IntrospectionServerPort is 0 (disabled).
+func (c DefaultConfig) introspectionAddr() string {
+	if c.IntrospectionServerPort == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", c.HostName, c.IntrospectionServerPort)
+}
+
+// tlsConfig builds a *tls.Config from TLSCertFile/TLSKeyFile, adding mTLS
+// client verification from TLSClientCAFile when set. It returns nil, nil if
+// neither TLSCertFile nor TLSKeyFile is set, and an error if only one of the
+// two is set: a typo'd env var should fail fast rather than silently fall
+// back to plaintext.
+func (c DefaultConfig) tlsConfig() (*tls.Config, error) {
+	if c.TLSCertFile == "" && c.TLSKeyFile == "" {
+		return nil, nil
+	}
+	if c.TLSCertFile == "" || c.TLSKeyFile == "" {
+		return nil, fmt.Errorf("tls: both TLSCertFile and TLSKeyFile must be set, got cert=%q key=%q", c.TLSCertFile, c.TLSKeyFile)
+	}
+	cert, err := tls.LoadX509KeyPair(c.TLSCertFile, c.TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load tls key pair: %w", err)
+	}
+	cfg := &tls.Config{Certificates: []tls.Certificate{cert}}
+	if c.TLSClientCAFile != "" {
+		pem, err := os.ReadFile(c.TLSClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read tls client ca: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", c.TLSClientCAFile)
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return cfg, nil
+}
+
+func (c DefaultConfig) h2c() bool {
+	return c.H2CEnabled
+}
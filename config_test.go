@@ -0,0 +1,57 @@
+package servicerunner
+
+import (
+	"crypto/tls"
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+)
+
+func TestDefaultConfigTLSConfig(t *testing.T) {
+	cases := map[string]struct {
+		cert    string
+		key     string
+		wantErr bool
+		wantNil bool
+	}{
+		"neither set":         {cert: "", key: "", wantNil: true},
+		"only cert set":       {cert: "cert.pem", key: "", wantErr: true},
+		"only key set":        {cert: "", key: "key.pem", wantErr: true},
+		"both set, bad paths": {cert: "missing-cert.pem", key: "missing-key.pem", wantErr: true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := DefaultConfig{TLSCertFile: tc.cert, TLSKeyFile: tc.key}
+			cfg, err := c.tlsConfig()
+			if tc.wantErr && err == nil {
+				t.Fatalf("tlsConfig() err = nil, want an error")
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("tlsConfig() err = %v, want nil", err)
+			}
+			if tc.wantNil && cfg != nil {
+				t.Fatalf("tlsConfig() cfg = %v, want nil", cfg)
+			}
+		})
+	}
+}
+
+func TestWithTLSConfigTakesPrecedenceOverEnvDerived(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	explicit := &tls.Config{ServerName: "explicit"}
+	cfg := DefaultConfig{
+		ServerName:  "test",
+		TLSCertFile: "missing-cert.pem",
+		TLSKeyFile:  "missing-key.pem",
+	}
+	r := New(cfg, logger, WithTesting(), WithTLSConfig(explicit)).(*runner)
+
+	srv, err := r.startHttpServer(nil, http.NewServeMux(), nil)
+	if err != nil {
+		t.Fatalf("startHttpServer err = %v, want nil (explicit TLS config should bypass the bad env-derived cert/key paths)", err)
+	}
+	if srv.TLSConfig != explicit {
+		t.Fatalf("startHttpServer TLSConfig = %v, want the explicit WithTLSConfig value", srv.TLSConfig)
+	}
+}
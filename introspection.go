@@ -0,0 +1,142 @@
+package servicerunner
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/pprof"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// funcName derives a Prometheus label from a RunFn, stripping the package
+// path so anonymous closures and methods both yield a short, stable name.
+func funcName(fn RunFn) string {
+	full := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
+	if idx := strings.LastIndex(full, "."); idx >= 0 {
+		return full[idx+1:]
+	}
+	return full
+}
+
+type healthCheck struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// runnerMetrics holds the Prometheus collectors automatically maintained for
+// every AddRunner/RunFn execution.
+type runnerMetrics struct {
+	registry *prometheus.Registry
+	started  *prometheus.CounterVec
+	failed   *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+func newRunnerMetrics() *runnerMetrics {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	factory := promauto.With(registry)
+	return &runnerMetrics{
+		registry: registry,
+		started: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "servicerunner_runner_started_total",
+			Help: "Number of times a registered RunFn has been started.",
+		}, []string{"runner"}),
+		failed: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "servicerunner_runner_failed_total",
+			Help: "Number of times a registered RunFn was halted because another run.Group actor returned an error.",
+		}, []string{"runner"}),
+		duration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "servicerunner_runner_duration_seconds",
+			Help: "How long a registered RunFn ran before returning.",
+		}, []string{"runner"}),
+	}
+}
+
+// HealthChecker registers a named health check exposed on the introspection
+// server's /healthz endpoint. All registered checks must succeed for the
+// endpoint to report healthy.
+func (r *runner) HealthChecker(name string, fn func(ctx context.Context) error) Runner {
+	r.healthChecks = append(r.healthChecks, healthCheck{name: name, fn: fn})
+	return r
+}
+
+// ReadinessGate returns a function callers can use to flip the readiness
+// flag exposed on the introspection server's /readyz endpoint. The service
+// starts not ready until it is called with true.
+func (r *runner) ReadinessGate() func(bool) {
+	return func(ready bool) {
+		if ready {
+			r.ready.Store(1)
+		} else {
+			r.ready.Store(0)
+		}
+	}
+}
+
+func (r *runner) handleHealthz(w http.ResponseWriter, req *http.Request) {
+	for _, check := range r.healthChecks {
+		if err := check.fn(req.Context()); err != nil {
+			r.logger.Error("health check failed", "check", check.name, "err", err)
+			http.Error(w, check.name+": "+err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ok"))
+}
+
+func (r *runner) handleReadyz(w http.ResponseWriter, _ *http.Request) {
+	if r.ready.Load() == 0 {
+		http.Error(w, "not ready", http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ok"))
+}
+
+// startIntrospectionServer binds the optional introspection listener and
+// registers it as a run.Group actor, returning the underlying *http.Server
+// so Run/Async can shut it down alongside everything else. It returns nil if
+// the introspection server is disabled or the Runner is running for testing.
+func (r *runner) startIntrospectionServer(cancel context.CancelFunc) *http.Server {
+	addr := r.serverConfig.introspectionAddr()
+	if addr == "" || r.forTesting {
+		return nil
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", r.handleHealthz)
+	mux.HandleFunc("/readyz", r.handleReadyz)
+	mux.Handle("/metrics", promhttp.HandlerFor(r.metrics.registry, promhttp.HandlerOpts{}))
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	srv := &http.Server{Addr: addr, Handler: mux}
+	r.Add(func() error {
+		if err := srv.ListenAndServe(); err != nil {
+			if !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+		}
+		return nil
+	}, func(err error) {
+		if err != nil {
+			r.logger.Error("introspection server failure", "service", r.serverConfig.Name(), "err", err)
+		}
+		// Unblock every other actor (including the shutdown coordinator,
+		// which is what actually stops this ListenAndServe call) whenever
+		// any actor in the group returns first.
+		cancel()
+	})
+	return srv
+}
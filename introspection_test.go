@@ -0,0 +1,71 @@
+package servicerunner
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+func TestHandleHealthzFailingCheck(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	r := New(NewConfig("test", 0), logger, WithTesting()).(*runner)
+	r.HealthChecker("db", func(ctx context.Context) error { return errors.New("connection refused") })
+
+	req := httptest.NewRequest("GET", "/healthz", nil)
+	rec := httptest.NewRecorder()
+	r.handleHealthz(rec, req)
+
+	if rec.Code != 503 {
+		t.Fatalf("handleHealthz status = %d, want 503", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "db") {
+		t.Fatalf("handleHealthz body = %q, want it to name the failing check", rec.Body.String())
+	}
+}
+
+func TestHandleReadyzBeforeAndAfterGate(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	r := New(NewConfig("test", 0), logger, WithTesting()).(*runner)
+
+	req := httptest.NewRequest("GET", "/readyz", nil)
+	rec := httptest.NewRecorder()
+	r.handleReadyz(rec, req)
+	if rec.Code != 503 {
+		t.Fatalf("handleReadyz status before gate = %d, want 503", rec.Code)
+	}
+
+	r.ReadinessGate()(true)
+
+	rec = httptest.NewRecorder()
+	r.handleReadyz(rec, req)
+	if rec.Code != 200 {
+		t.Fatalf("handleReadyz status after gate = %d, want 200", rec.Code)
+	}
+}
+
+func TestAddRunnerActorsScrapeStartedMetric(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	r := New(NewConfig("test", 0), logger, WithTesting()).(*runner)
+	r.AddRunner(func(ctx context.Context) {})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r.addRunnerActors(ctx, cancel)
+	if err := r.Group.Run(); err != nil {
+		t.Fatalf("Group.Run err = %v, want nil", err)
+	}
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	rec := httptest.NewRecorder()
+	promhttp.HandlerFor(r.metrics.registry, promhttp.HandlerOpts{}).ServeHTTP(rec, req)
+
+	if !strings.Contains(rec.Body.String(), "servicerunner_runner_started_total") {
+		t.Fatalf("metrics scrape missing servicerunner_runner_started_total, got %q", rec.Body.String())
+	}
+}
@@ -0,0 +1,30 @@
+package servicerunner
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/coreos/go-systemd/v22/activation"
+)
+
+// activationListeners is a var so tests can stub systemd socket activation
+// without needing a real LISTEN_FDS file descriptor.
+var activationListeners = activation.ListenersWithNames
+
+// resolveListener picks the net.Listener startHttpServer binds to, in order
+// of preference: an explicitly supplied WithListener, a systemd-activated
+// socket named after Config.Name(), and finally a plain net.Listen on
+// Config.addr(). This enables zero-downtime restarts via socket handoff.
+func (r *runner) resolveListener(a Config) (net.Listener, error) {
+	if r.listener != nil {
+		return r.listener, nil
+	}
+	named, err := activationListeners()
+	if err != nil {
+		return nil, fmt.Errorf("systemd activation: %w", err)
+	}
+	if listeners, ok := named[a.Name()]; ok && len(listeners) > 0 {
+		return listeners[0], nil
+	}
+	return net.Listen("tcp", a.addr())
+}
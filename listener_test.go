@@ -0,0 +1,84 @@
+package servicerunner
+
+import (
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+)
+
+func TestResolveListenerPrefersExplicitListener(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	explicit, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer explicit.Close()
+
+	restore := stubActivationListeners(map[string][]net.Listener{
+		"test": {mustListen(t)},
+	})
+	defer restore()
+
+	r := New(NewConfig("test", 0), logger, WithListener(explicit)).(*runner)
+	ln, err := r.resolveListener(r.serverConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ln != explicit {
+		t.Fatal("resolveListener should prefer the WithListener option over an activated socket")
+	}
+}
+
+func TestResolveListenerPrefersActivatedSocketOverNetListen(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	activated := mustListen(t)
+	defer activated.Close()
+
+	restore := stubActivationListeners(map[string][]net.Listener{
+		"test": {activated},
+	})
+	defer restore()
+
+	r := New(NewConfig("test", 0), logger).(*runner)
+	ln, err := r.resolveListener(r.serverConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ln != activated {
+		t.Fatal("resolveListener should prefer a systemd-activated socket over net.Listen")
+	}
+}
+
+func TestResolveListenerFallsBackToNetListen(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	restore := stubActivationListeners(nil)
+	defer restore()
+
+	r := New(NewConfigWithHost("test", "127.0.0.1", 0), logger).(*runner)
+	ln, err := r.resolveListener(r.serverConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	if ln.Addr().Network() != "tcp" {
+		t.Fatalf("resolveListener fallback network = %s, want tcp", ln.Addr().Network())
+	}
+}
+
+func stubActivationListeners(named map[string][]net.Listener) (restore func()) {
+	prev := activationListeners
+	activationListeners = func() (map[string][]net.Listener, error) {
+		return named, nil
+	}
+	return func() { activationListeners = prev }
+}
+
+func mustListen(t *testing.T) net.Listener {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ln
+}
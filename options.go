@@ -0,0 +1,76 @@
+package servicerunner
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"time"
+)
+
+// defaultShutdownTimeout bounds how long Run waits for the HTTP server and
+// each registered closer to shut down gracefully before giving up.
+const defaultShutdownTimeout = 15 * time.Second
+
+// Option configures a Runner created via New or NewEnv.
+type Option func(*runner)
+
+// WithShutdownTimeout bounds how long Run waits for the HTTP server to
+// complete http.Server.Shutdown, and how long it waits for each registered
+// closer to return, before moving on. Defaults to defaultShutdownTimeout.
+func WithShutdownTimeout(d time.Duration) Option {
+	return func(r *runner) {
+		r.shutdownTimeout = d
+	}
+}
+
+// WithDrainDelay adds a delay between the shutdown signal being received and
+// the HTTP server being closed, giving in-flight requests and any load
+// balancer that hasn't yet noticed the instance is going away time to drain.
+func WithDrainDelay(d time.Duration) Option {
+	return func(r *runner) {
+		r.drainDelay = d
+	}
+}
+
+// WithTesting marks the Runner as running under test: the listener is never
+// bound and the HTTP/introspection servers are never served, mirroring the
+// previous `testing ...bool` argument to New.
+func WithTesting() Option {
+	return func(r *runner) {
+		r.forTesting = true
+	}
+}
+
+// HookFn is a lifecycle hook registered via Runner.PreRun, Runner.PostStart
+// or Runner.PreStop.
+type HookFn func(ctx context.Context) error
+
+// WithExitFunc overrides the func called by Run/Async when RunE/AsyncE
+// return an error, in place of the default os.Exit(1). Callers embedding
+// Runner in a larger binary can use this to plug in their own fatal handler
+// instead of terminating the process.
+func WithExitFunc(fn func(error)) Option {
+	return func(r *runner) {
+		r.exitFunc = fn
+	}
+}
+
+// WithListener bypasses Config.addr() entirely, having Run serve on an
+// already-bound net.Listener. It takes precedence over systemd socket
+// activation, and is useful for injecting an ephemeral listener in tests or
+// for handing a listener off between process generations.
+func WithListener(ln net.Listener) Option {
+	return func(r *runner) {
+		r.listener = ln
+	}
+}
+
+// WithTLSConfig configures the HTTP server to serve TLS using cfg, taking
+// precedence over the SERVER_TLS_CERT_FILE/SERVER_TLS_KEY_FILE/
+// SERVER_TLS_CLIENT_CA env vars. Use this for programmatic certificate
+// sources such as cert rotation or SPIFFE/SPIRE workload identities.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(r *runner) {
+		r.tlsConfig = cfg
+	}
+}
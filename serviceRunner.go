@@ -2,13 +2,20 @@ package servicerunner
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
+	"fmt"
 	"github.com/oklog/run"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync/atomic"
+	"time"
 )
 
 // SigContext return a context and a cancellation function which is invoked on the following
@@ -52,8 +59,15 @@ type Runner interface {
 	//
 	//  Stop signals: syscall.SIGINT, syscall.SIGTERM, syscall.SIGKILL
 	Run(ctx context.Context, mu http.Handler)
+	// RunE behaves like Run but returns the underlying error instead of
+	// exiting the process, making it suitable for tests and for embedding
+	// in larger binaries.
+	RunE(ctx context.Context, mu http.Handler) error
 	// Async starts only the runners without the http.Handler
 	Async(ctx context.Context)
+	// AsyncE behaves like Async but returns the underlying error instead of
+	// exiting the process.
+	AsyncE(ctx context.Context) error
 	// OnClose will add a cleanup function to be called when the runner is stopped.
 	OnClose(cleanup ...io.Closer) Runner
 	// AddRunner adds a func(ctx) that will be executed when invoking Run (in a separate goroutine).
@@ -61,28 +75,50 @@ type Runner interface {
 	// NOTE: if a RunFn return an error or exits because its work is done, all the underlying runners
 	// and the main server (if any) are halted.
 	AddRunner(runners ...RunFn) Runner
+	// PreRun registers a hook run sequentially, in registration order, before
+	// Run/Async start the underlying run.Group. If any hook returns an error,
+	// startup is aborted: no runner goroutine is started and no closer runs.
+	PreRun(hooks ...HookFn) Runner
+	// PostStart registers a hook run once the HTTP listener is bound and
+	// accepting connections. Hook errors are logged but do not stop the
+	// service.
+	PostStart(hooks ...HookFn) Runner
+	// PreStop registers a hook run after the Run context is cancelled, before
+	// the HTTP server is shut down and the registered closers run. Hook
+	// errors are logged but do not prevent shutdown from proceeding.
+	PreStop(hooks ...HookFn) Runner
+	// HealthChecker registers a named health check exposed on the
+	// introspection server's /healthz endpoint, when enabled via
+	// Config.IntrospectionPort.
+	HealthChecker(name string, fn func(ctx context.Context) error) Runner
+	// ReadinessGate returns a function used to flip the readiness flag
+	// exposed on the introspection server's /readyz endpoint. The service
+	// starts not ready.
+	ReadinessGate() func(bool)
 }
 
 // NewEnv return a Runner with NewEnvConfig.
-func NewEnv(logger *slog.Logger, testing ...bool) Runner {
+func NewEnv(logger *slog.Logger, opts ...Option) Runner {
 	cfg := NewEnvConfig()
-	return New(cfg, logger, testing...)
+	return New(cfg, logger, opts...)
 }
 
 // New initializes a new Runner.
-func New(serverConfig Config, logger *slog.Logger, testing ...bool) Runner {
-	forTesting := false
-	if len(testing) == 1 && testing[0] {
-		forTesting = true
+func New(serverConfig Config, logger *slog.Logger, opts ...Option) Runner {
+	r := &runner{
+		Group:           &run.Group{},
+		serverConfig:    serverConfig,
+		logger:          logger,
+		runners:         make([]RunFn, 0),
+		cleanup:         make([]io.Closer, 0),
+		shutdownTimeout: defaultShutdownTimeout,
+		metrics:         newRunnerMetrics(),
+		exitFunc:        func(error) { os.Exit(1) },
 	}
-	return &runner{
-		Group:        &run.Group{},
-		serverConfig: serverConfig,
-		logger:       logger,
-		runners:      make([]RunFn, 0),
-		cleanup:      make([]io.Closer, 0),
-		forTesting:   forTesting,
+	for _, opt := range opts {
+		opt(r)
 	}
+	return r
 }
 
 type runner struct {
@@ -92,7 +128,20 @@ type runner struct {
 	runners      []RunFn
 	cleanup      []io.Closer
 
-	forTesting bool
+	preRun    []HookFn
+	postStart []HookFn
+	preStop   []HookFn
+
+	healthChecks []healthCheck
+	ready        atomic.Int32
+	metrics      *runnerMetrics
+	listener     net.Listener
+	tlsConfig    *tls.Config
+
+	forTesting      bool
+	shutdownTimeout time.Duration
+	drainDelay      time.Duration
+	exitFunc        func(error)
 }
 
 func (r *runner) AddRunner(runners ...RunFn) Runner {
@@ -105,16 +154,81 @@ func (r *runner) OnClose(cleanup ...io.Closer) Runner {
 	return r
 }
 
-func (r *runner) startHttpServer(mu http.Handler) io.Closer {
-	if r.forTesting {
-		return io.NopCloser(nil)
+func (r *runner) PreRun(hooks ...HookFn) Runner {
+	r.preRun = append(r.preRun, hooks...)
+	return r
+}
+
+func (r *runner) PostStart(hooks ...HookFn) Runner {
+	r.postStart = append(r.postStart, hooks...)
+	return r
+}
+
+func (r *runner) PreStop(hooks ...HookFn) Runner {
+	r.preStop = append(r.preStop, hooks...)
+	return r
+}
+
+// runPreRun runs the PreRun hooks sequentially, stopping at the first error.
+func (r *runner) runPreRun(ctx context.Context) error {
+	for _, hook := range r.preRun {
+		if err := hook(ctx); err != nil {
+			return err
+		}
 	}
+	return nil
+}
+
+// runPostStart runs the PostStart hooks sequentially, logging any error
+// without aborting the service.
+func (r *runner) runPostStart(ctx context.Context) {
+	for _, hook := range r.postStart {
+		if err := hook(ctx); err != nil {
+			r.logger.Error("post-start hook failed", "service", r.serverConfig.Name(), "err", err)
+		}
+	}
+}
+
+// runPreStop runs the PreStop hooks sequentially, logging any error without
+// aborting shutdown.
+func (r *runner) runPreStop(ctx context.Context) {
+	for _, hook := range r.preStop {
+		if err := hook(ctx); err != nil {
+			r.logger.Error("pre-stop hook failed", "service", r.serverConfig.Name(), "err", err)
+		}
+	}
+}
+
+func (r *runner) startHttpServer(ctx context.Context, mu http.Handler, cancel context.CancelFunc) (*http.Server, error) {
 	a := r.serverConfig
-	srv := &http.Server{Addr: a.addr(), Handler: mu}
+	tlsCfg := r.tlsConfig
+	if tlsCfg == nil {
+		cfg, err := a.tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+		tlsCfg = cfg
+	}
+	handler := mu
+	if tlsCfg == nil && a.h2c() {
+		handler = h2c.NewHandler(mu, &http2.Server{})
+	}
+	srv := &http.Server{Addr: a.addr(), Handler: handler, TLSConfig: tlsCfg}
+	if r.forTesting {
+		return srv, nil
+	}
+	ln, err := r.resolveListener(a)
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg != nil {
+		ln = tls.NewListener(ln, tlsCfg)
+	}
 	r.Add(func() error {
-		if err := srv.ListenAndServe(); err != nil {
+		go r.runPostStart(ctx)
+		if err := srv.Serve(ln); err != nil {
 			if !errors.Is(err, http.ErrServerClosed) {
-				return err
+				return fmt.Errorf("http server: %w", err)
 			}
 		}
 		return nil
@@ -122,58 +236,154 @@ func (r *runner) startHttpServer(mu http.Handler) io.Closer {
 		if err != nil {
 			r.logger.Error("http server failure", "service", r.serverConfig.Name(), "err", err)
 		}
+		// Unblock every other actor (including the shutdown coordinator,
+		// which is what actually stops this Serve call) whenever any actor
+		// in the group returns first.
+		cancel()
 	})
-	return srv
+	return srv, nil
 }
 
-func (r *runner) Async(ctx context.Context) {
-	for _, runFn := range r.runners {
+// closeAll closes the registered cleanup closers in reverse (LIFO)
+// registration order, bounding each one to shutdownTimeout and logging
+// rather than swallowing any error it returns.
+func (r *runner) closeAll() {
+	for i := len(r.cleanup) - 1; i >= 0; i-- {
+		if err := closeWithTimeout(r.cleanup[i], r.shutdownTimeout); err != nil {
+			r.logger.Error("closer failed", "index", i, "closer", fmt.Sprintf("%T", r.cleanup[i]), "err", err)
+		}
+	}
+}
+
+// closeWithTimeout runs c.Close() in its own goroutine and gives up after
+// timeout, since io.Closer offers no way to pass a context.
+func closeWithTimeout(c io.Closer, timeout time.Duration) error {
+	done := make(chan error, 1)
+	go func() { done <- c.Close() }()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(timeout):
+		return fmt.Errorf("closer did not return within %s", timeout)
+	}
+}
+
+// addRunnerActors registers each AddRunner func(ctx) as a run.Group actor,
+// recording started/duration/failed Prometheus metrics labeled by the
+// RunFn's function name. Multiple RunFns sharing the same call site (a
+// common AddRunner usage, e.g. created in a loop) would otherwise collapse
+// onto one label, so the label is disambiguated with each RunFn's
+// registration index.
+func (r *runner) addRunnerActors(ctx context.Context, cancel context.CancelFunc) {
+	for i, runFn := range r.runners {
+		fn := runFn
+		name := fmt.Sprintf("%s-%d", funcName(fn), i)
 		r.Add(func() error {
-			runFn(ctx)
+			r.metrics.started.WithLabelValues(name).Inc()
+			start := time.Now()
+			fn(ctx)
+			r.metrics.duration.WithLabelValues(name).Observe(time.Since(start).Seconds())
 			return nil
-		}, func(error) {
-
+		}, func(err error) {
+			if err != nil {
+				r.metrics.failed.WithLabelValues(name).Inc()
+			}
+			// A RunFn finishing (per its doc comment) halts the main server
+			// and every other runner, so wake the shutdown coordinator.
+			cancel()
 		})
 	}
+}
+
+// Async starts only the runners without the http.Handler, exiting the
+// process via the configured exit func (os.Exit(1) by default) on failure.
+// See AsyncE for a variant that returns the error instead.
+func (r *runner) Async(ctx context.Context) {
+	if err := r.AsyncE(ctx); err != nil {
+		r.logger.Error("group runner failure", "mode", "async", "err", err)
+		r.exitFunc(err)
+	}
+}
+
+func (r *runner) AsyncE(ctx context.Context) error {
+	if err := r.runPreRun(ctx); err != nil {
+		return fmt.Errorf("pre-run hook: %w", err)
+	}
+	// runCtx is cancelled either by ctx itself or by any actor's interrupt
+	// func below, so the coordinator (and anything it shuts down) always
+	// wakes up instead of leaving Group.Run hanging.
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if introspectionSrv := r.startIntrospectionServer(cancel); introspectionSrv != nil {
+		r.Add(func() error {
+			<-runCtx.Done()
+			shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), r.shutdownTimeout)
+			defer cancelShutdown()
+			if err := introspectionSrv.Shutdown(shutdownCtx); err != nil {
+				return fmt.Errorf("introspection server: %w", err)
+			}
+			return nil
+		}, func(error) { cancel() })
+	}
+	r.addRunnerActors(runCtx, cancel)
 	keyValues := make([]any, 0)
 	if r.forTesting {
 		keyValues = append(keyValues, "TESTING-MODE", "ON")
 	}
 	r.logger.Debug("async runner started", keyValues...)
-	if err := r.Group.Run(); err != nil {
-		r.logger.Error("group runner failure", "mode", "async", "err", err)
-		os.Exit(1)
-	}
+	return r.Group.Run()
 }
 
+// Run will listen and serve with the given http.Handler, exiting the process
+// via the configured exit func (os.Exit(1) by default) on failure. See RunE
+// for a variant that returns the error instead.
 func (r *runner) Run(ctx context.Context, mu http.Handler) {
-	srv := r.startHttpServer(mu)
+	if err := r.RunE(ctx, mu); err != nil {
+		r.logger.Error("group runner failure", "mode", "sync", "err", err)
+		r.exitFunc(err)
+	}
+}
+
+func (r *runner) RunE(ctx context.Context, mu http.Handler) error {
+	if err := r.runPreRun(ctx); err != nil {
+		return fmt.Errorf("pre-run hook: %w", err)
+	}
+	// runCtx is cancelled either by ctx itself or by any actor's interrupt
+	// func below, so the coordinator (and anything it shuts down) always
+	// wakes up instead of leaving Group.Run hanging.
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	srv, err := r.startHttpServer(ctx, mu, cancel)
+	if err != nil {
+		return fmt.Errorf("http server bind: %w", err)
+	}
+	introspectionSrv := r.startIntrospectionServer(cancel)
 	{
 		r.Add(func() error {
-			<-ctx.Done()
-			_ = srv.Close()
-			for _, closer := range r.cleanup {
-				_ = closer.Close()
+			<-runCtx.Done()
+			r.runPreStop(ctx)
+			if r.drainDelay > 0 {
+				time.Sleep(r.drainDelay)
 			}
+			shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), r.shutdownTimeout)
+			defer cancelShutdown()
+			if err := srv.Shutdown(shutdownCtx); err != nil {
+				r.logger.Error("http server shutdown failed", "service", r.serverConfig.Name(), "err", err)
+			}
+			if introspectionSrv != nil {
+				if err := introspectionSrv.Shutdown(shutdownCtx); err != nil {
+					r.logger.Error("introspection server shutdown failed", "service", r.serverConfig.Name(), "err", err)
+				}
+			}
+			r.closeAll()
 			return nil
-		}, func(err error) {})
-	}
-	for _, runFn := range r.runners {
-		r.Add(func() error {
-			runFn(ctx)
-			return nil
-		}, func(error) {
-
-		})
+		}, func(err error) { cancel() })
 	}
+	r.addRunnerActors(runCtx, cancel)
 	keyValues := make([]any, 0)
 	if r.forTesting {
 		keyValues = append(keyValues, "TESTING-MODE", "ON")
 	}
 	r.logger.Debug("group runner start", keyValues...)
-	err := r.Group.Run()
-	if err != nil {
-		r.logger.Error("group runner failure", "mode", "sync", "err", err)
-		os.Exit(1)
-	}
+	return r.Group.Run()
 }
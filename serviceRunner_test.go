@@ -0,0 +1,117 @@
+package servicerunner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCloseAllLIFOOrder(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	r := New(NewConfig("test", 0), logger, WithTesting()).(*runner)
+
+	var order []int
+	r.OnClose(
+		Closer(func() error { order = append(order, 1); return nil }),
+		Closer(func() error { order = append(order, 2); return nil }),
+		Closer(func() error { order = append(order, 3); return nil }),
+	)
+
+	r.closeAll()
+
+	want := []int{3, 2, 1}
+	if !reflect.DeepEqual(order, want) {
+		t.Fatalf("closeAll order = %v, want %v", order, want)
+	}
+}
+
+func TestCloseAllTimesOutSlowCloser(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	r := New(NewConfig("test", 0), logger, WithTesting(), WithShutdownTimeout(10*time.Millisecond)).(*runner)
+
+	r.OnClose(Closer(func() error {
+		time.Sleep(100 * time.Millisecond)
+		return nil
+	}))
+
+	start := time.Now()
+	r.closeAll()
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Fatalf("closeAll took %s, want bounded by shutdown timeout", elapsed)
+	}
+	if !strings.Contains(buf.String(), "closer failed") {
+		t.Fatalf("expected a logged closer timeout, got %q", buf.String())
+	}
+}
+
+func TestPreRunAbortsBeforeStartup(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	r := New(NewConfig("test", 0), logger, WithTesting())
+
+	preRunErr := errors.New("boom")
+	r.PreRun(func(ctx context.Context) error { return preRunErr })
+
+	var started atomic.Bool
+	r.AddRunner(func(ctx context.Context) { started.Store(true) })
+
+	var closed atomic.Bool
+	r.OnClose(Closer(func() error { closed.Store(true); return nil }))
+
+	err := r.RunE(context.Background(), http.NewServeMux())
+	if !errors.Is(err, preRunErr) {
+		t.Fatalf("RunE err = %v, want wrapping %v", err, preRunErr)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	if started.Load() {
+		t.Fatal("RunFn goroutine should not have started after a PreRun failure")
+	}
+	if closed.Load() {
+		t.Fatal("closers should not run when startup is aborted by PreRun")
+	}
+}
+
+func TestRunEDoesNotHangWhenRunnerFinishes(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	r := New(NewConfig("test", 0), logger, WithTesting())
+	r.AddRunner(func(ctx context.Context) {})
+
+	done := make(chan error, 1)
+	go func() { done <- r.RunE(context.Background(), http.NewServeMux()) }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("RunE err = %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("RunE hung after a RunFn finished")
+	}
+}
+
+func TestAsyncEDoesNotHangWhenRunnerFinishes(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	r := New(NewConfig("test", 0), logger, WithTesting())
+	r.AddRunner(func(ctx context.Context) {})
+
+	done := make(chan error, 1)
+	go func() { done <- r.AsyncE(context.Background()) }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("AsyncE err = %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("AsyncE hung after a RunFn finished")
+	}
+}